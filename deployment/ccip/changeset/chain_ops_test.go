@@ -0,0 +1,89 @@
+package changeset
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+)
+
+func TestNewChainOpsForFamily_UnsupportedFamily(t *testing.T) {
+	_, err := newChainOpsForFamily(ChainFamily("unknown"), deployment.Environment{}, CCIPOnChainState{}, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported chain family")
+}
+
+func TestEVMChainOps_Family(t *testing.T) {
+	ops := &EVMChainOps{chainSelector: 1}
+	require.Equal(t, ChainFamilyEVM, ops.Family())
+	require.Equal(t, uint64(1), ops.ChainSelector())
+}
+
+// TestChainOps_NotYetImplementedMethods is parameterized over the non-EVM ChainOps
+// implementations and the write paths each has not yet migrated: every call must fail loudly
+// rather than silently no-op.
+func TestChainOps_NotYetImplementedMethods(t *testing.T) {
+	sol := &SolanaChainOps{chainSelector: 2}
+	apt := &AptosChainOps{chainSelector: 3}
+
+	cases := []struct {
+		name string
+		call func() error
+	}{
+		{"solana UpdateFeeQuoterDests", func() error { _, err := sol.UpdateFeeQuoterDests(nil); return err }},
+		{"solana UpdateOffRampSources", func() error { _, err := sol.UpdateOffRampSources(nil); return err }},
+		{"solana UpdateRouterRamps", func() error { _, err := sol.UpdateRouterRamps(RouterUpdates{}); return err }},
+		{"aptos UpdateOnRampDests", func() error { _, err := apt.UpdateOnRampDests(nil); return err }},
+		{"aptos UpdateFeeQuoterDests", func() error { _, err := apt.UpdateFeeQuoterDests(nil); return err }},
+		{"aptos UpdateOffRampSources", func() error { _, err := apt.UpdateOffRampSources(nil); return err }},
+		{"aptos UpdateRouterRamps", func() error { _, err := apt.UpdateRouterRamps(RouterUpdates{}); return err }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Error(t, tc.call())
+		})
+	}
+}
+
+func TestSolanaChainOps_UpdateOnRampDests_EmptyUpdates(t *testing.T) {
+	sol := &SolanaChainOps{chainSelector: 2}
+	batch, err := sol.UpdateOnRampDests(nil)
+	require.NoError(t, err)
+	require.Empty(t, batch.Instructions)
+}
+
+func TestEncodeUpdateDestChainConfigInstruction(t *testing.T) {
+	updates := map[uint64]OnRampDestinationUpdate{
+		5: {IsEnabled: true, AllowListEnabled: false},
+		1: {IsEnabled: true, AllowListEnabled: true},
+	}
+
+	data := encodeUpdateDestChainConfigInstruction(updates)
+
+	wantDisc := anchorDiscriminator("update_dest_chain_config")
+	require.Equal(t, wantDisc[:], data[:8])
+	require.Equal(t, uint32(2), binary.LittleEndian.Uint32(data[8:12]))
+
+	// Entries are encoded in ascending dest chain selector order so the instruction data is
+	// deterministic regardless of map iteration order.
+	require.Equal(t, uint64(1), binary.LittleEndian.Uint64(data[12:20]))
+	require.Equal(t, byte(1), data[20]) // is_enabled
+	require.Equal(t, byte(1), data[21]) // allow_list_enabled
+	require.Equal(t, uint64(5), binary.LittleEndian.Uint64(data[22:30]))
+	require.Equal(t, byte(1), data[30])
+	require.Equal(t, byte(0), data[31])
+
+	// Re-encoding must be stable.
+	require.Equal(t, data, encodeUpdateDestChainConfigInstruction(updates))
+}
+
+func TestSolanaChainOps_UpdateOnRampDests_RealInstruction(t *testing.T) {
+	sol := &SolanaChainOps{chainSelector: 2, programID: "OnRampProgram111"}
+	batch, err := sol.UpdateOnRampDests(map[uint64]OnRampDestinationUpdate{7: {IsEnabled: true}})
+	require.NoError(t, err)
+	require.Len(t, batch.Instructions, 1)
+	require.Equal(t, "OnRampProgram111", batch.Instructions[0].ProgramID)
+	require.Equal(t, encodeUpdateDestChainConfigInstruction(map[uint64]OnRampDestinationUpdate{7: {IsEnabled: true}}), batch.Instructions[0].Data)
+}
@@ -131,7 +131,7 @@ func Test_ActiveCandidate(t *testing.T) {
 	require.NoError(t, err)
 	assertTimelockOwnership(t, tenv, allChains, state)
 
-	sendMsg := func() {
+	sendMsg := func(assertMerkleRoot bool) {
 		latesthdr, err := tenv.Env.Chains[dest].Client.HeaderByNumber(testcontext.Get(t), nil)
 		require.NoError(t, err)
 		block := latesthdr.Number.Uint64()
@@ -162,12 +162,16 @@ func Test_ActiveCandidate(t *testing.T) {
 		)
 
 		// Confirm execution of the message
-		ConfirmCommitForAllWithExpectedSeqNums(t, tenv.Env, state, expectedSeqNum, startBlocks)
+		if assertMerkleRoot {
+			ConfirmCommitWithMerkleRootForAllWithExpectedSeqNums(t, tenv.Env, state, expectedSeqNum, startBlocks)
+		} else {
+			ConfirmCommitForAllWithExpectedSeqNums(t, tenv.Env, state, expectedSeqNum, startBlocks)
+		}
 		ConfirmExecWithSeqNrsForAll(t, tenv.Env, state, expectedSeqNumExec, startBlocks)
 	}
 
 	// send a message from source to dest and ensure that it gets executed
-	sendMsg()
+	sendMsg(false)
 
 	var (
 		capReg   = state.Chains[tenv.HomeChainSel].CapabilityRegistry
@@ -248,6 +252,7 @@ func Test_ActiveCandidate(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEqual(t, candidateDigestExec, candidateDigestExecBefore)
 
-	// send a message from source to dest and ensure that it gets executed after the candidate config is set
-	sendMsg()
+	// send a message from source to dest and ensure that it gets executed after the candidate config is set,
+	// and that the commit report's merkle root actually covers it
+	sendMsg(true)
 }
\ No newline at end of file
@@ -0,0 +1,164 @@
+package changeset
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+)
+
+// ConfirmCommitWithMerkleRootForAllWithExpectedSeqNums is a stricter variant of
+// ConfirmCommitForAllWithExpectedSeqNums: for every (source, dest) pair it not only waits for a
+// CommitReportAccepted event covering the expected sequence number, but locally reconstructs the
+// merkle tree over every message sent on that source chain in the committed range and asserts the
+// expected message's leaf hash is actually included under the report's merkle root. This catches
+// the case where a commit report is produced for the right sequence number range but the reported
+// root doesn't actually cover the message (e.g. a MultiOCR3 candidate/active mismatch).
+func ConfirmCommitWithMerkleRootForAllWithExpectedSeqNums(
+	t *testing.T,
+	env deployment.Environment,
+	state CCIPOnChainState,
+	expectedSeqNum map[SourceDestPair]uint64,
+	startBlocks map[uint64]*uint64,
+) {
+	t.Helper()
+
+	// Reuse the existing seq-num-level wait so we don't race the commit report being mined.
+	ConfirmCommitForAllWithExpectedSeqNums(t, env, state, expectedSeqNum, startBlocks)
+
+	for pair, seqNum := range expectedSeqNum {
+		destState := state.Chains[pair.DestChainSelector]
+		report, minSeqNr, maxSeqNr := findCommitReportCoveringSeqNum(t, destState, pair, seqNum, startBlocks[pair.DestChainSelector])
+
+		leaves := messageLeafHashesInRange(t, state, pair.SourceChainSelector, minSeqNr, maxSeqNr)
+		expectedLeaf, ok := leaves[seqNum]
+		require.True(t, ok, "no SendRequested event found for seq num %d on source chain %d in range [%d,%d]",
+			seqNum, pair.SourceChainSelector, minSeqNr, maxSeqNr)
+
+		orderedLeaves := make([][32]byte, 0, len(leaves))
+		for sn := minSeqNr; sn <= maxSeqNr; sn++ {
+			leaf, ok := leaves[sn]
+			require.True(t, ok, "missing SendRequested event for seq num %d in reported range [%d,%d] on source chain %d",
+				sn, minSeqNr, maxSeqNr, pair.SourceChainSelector)
+			orderedLeaves = append(orderedLeaves, leaf)
+		}
+
+		computedRoot := computeMerkleRoot(orderedLeaves)
+		require.Equal(t, report, computedRoot,
+			"reconstructed merkle root for source %d dest %d (seq range [%d,%d]) does not match the reported root; expected leaf for seq %d was %x, reconstructed leaves were %x",
+			pair.SourceChainSelector, pair.DestChainSelector, minSeqNr, maxSeqNr, seqNum, expectedLeaf, orderedLeaves)
+	}
+}
+
+// findCommitReportCoveringSeqNum scans CommitReportAccepted events on dest for pair's source chain
+// and returns the reported merkle root plus the [minSeqNr, maxSeqNr] range it covers, for the
+// report that contains seqNum. Fails the test if no such report is found.
+func findCommitReportCoveringSeqNum(
+	t *testing.T,
+	destState CCIPChainState,
+	pair SourceDestPair,
+	seqNum uint64,
+	startBlock *uint64,
+) (root [32]byte, minSeqNr, maxSeqNr uint64) {
+	t.Helper()
+
+	iter, err := destState.OffRamp.FilterCommitReportAccepted(&bind.FilterOpts{Start: startBlockOrZero(startBlock)})
+	require.NoError(t, err)
+	defer iter.Close()
+
+	for iter.Next() {
+		for _, mr := range iter.Event.MerkleRoots {
+			if mr.SourceChainSelector != pair.SourceChainSelector {
+				continue
+			}
+			if seqNum >= mr.MinSeqNr && seqNum <= mr.MaxSeqNr {
+				return mr.MerkleRoot, mr.MinSeqNr, mr.MaxSeqNr
+			}
+		}
+	}
+	require.Fail(t, "no commit report found covering seq num", "source %d dest %d seq %d", pair.SourceChainSelector, pair.DestChainSelector, seqNum)
+	return [32]byte{}, 0, 0
+}
+
+func startBlockOrZero(b *uint64) uint64 {
+	if b == nil {
+		return 0
+	}
+	return *b
+}
+
+// messageLeafHashesInRange returns, for every CCIPMessageSent event on sourceSel with a sequence
+// number in [minSeqNr, maxSeqNr], the leaf hash that would have been included in the source
+// chain's outgoing merkle tree for that message. This must be the message ID the OnRamp itself
+// computed (Internal._hash, a domain-separated hash over the message header/sender/receiver/data/
+// token fields) rather than a hash of the raw log bytes, since the log's ABI-encoded event data
+// includes fields (e.g. block metadata) that are not part of what the OffRamp's merkle root covers.
+func messageLeafHashesInRange(
+	t *testing.T,
+	state CCIPOnChainState,
+	sourceSel uint64,
+	minSeqNr, maxSeqNr uint64,
+) map[uint64][32]byte {
+	t.Helper()
+
+	sourceState := state.Chains[sourceSel]
+	iter, err := sourceState.OnRamp.FilterCCIPMessageSent(&bind.FilterOpts{})
+	require.NoError(t, err)
+	defer iter.Close()
+
+	leaves := make(map[uint64][32]byte)
+	for iter.Next() {
+		seqNum := iter.Event.SequenceNumber
+		if seqNum < minSeqNr || seqNum > maxSeqNr {
+			continue
+		}
+		leaves[seqNum] = iter.Event.Message.Header.MessageId
+	}
+	return leaves
+}
+
+// internalDomainSeparator matches MerkleMultiProof.sol's INTERNAL_DOMAIN_SEPARATOR: every internal
+// node hashes its two children under this separator, so an internal node hash can never be
+// reinterpreted as a leaf (which carries no such prefix).
+const internalDomainSeparator byte = 0x01
+
+// computeMerkleRoot rebuilds a CCIP-style merkle root from an ordered list of message leaves,
+// matching MerkleMultiProof.sol: a lone leaf is the root unchanged, and otherwise adjacent pairs
+// are combined left-to-right under the internal domain separator (order preserved, NOT sorted —
+// the real tree is not commutative, unlike a naive sorted-pair hash) until a single root remains. A
+// node promoted to the next level without a sibling is carried up unchanged.
+func computeMerkleRoot(leaves [][32]byte) [32]byte {
+	if len(leaves) == 0 {
+		return [32]byte{}
+	}
+	level := leaves
+	for len(level) > 1 {
+		next := make([][32]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			next = append(next, hashInternalNode(level[i], level[i+1]))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// hashInternalNode combines left and right the way abi.encode(INTERNAL_DOMAIN_SEPARATOR, left,
+// right) would: a 32-byte word holding the separator, followed by left and right as-is (not
+// sorted), so swapping left and right changes the result.
+func hashInternalNode(left, right [32]byte) [32]byte {
+	var separatorWord [32]byte
+	separatorWord[31] = internalDomainSeparator
+
+	var buf []byte
+	buf = append(buf, separatorWord[:]...)
+	buf = append(buf, left[:]...)
+	buf = append(buf, right[:]...)
+	return [32]byte(crypto.Keccak256Hash(buf))
+}
@@ -0,0 +1,56 @@
+package changeset
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeMerkleRoot(t *testing.T) {
+	require.Equal(t, [32]byte{}, computeMerkleRoot(nil))
+
+	leaf := [32]byte{1}
+	require.Equal(t, leaf, computeMerkleRoot([][32]byte{leaf}), "a single leaf is the root unchanged")
+
+	a, b := [32]byte{1}, [32]byte{2}
+	require.Equal(t, hashInternalNode(a, b), computeMerkleRoot([][32]byte{a, b}))
+	require.NotEqual(t, computeMerkleRoot([][32]byte{a, b}), computeMerkleRoot([][32]byte{b, a}),
+		"the tree is not commutative: swapping leaf order must change the root")
+
+	// An odd leaf count promotes the last leaf unchanged to the next level instead of pairing it
+	// with itself.
+	c := [32]byte{3}
+	odd := computeMerkleRoot([][32]byte{a, b, c})
+	even := computeMerkleRoot([][32]byte{hashInternalNode(a, b), c})
+	require.Equal(t, even, odd)
+}
+
+// TestComputeMerkleRoot_MultiMessage exercises the multi-leaf branch directly (four messages,
+// forcing two levels of internal hashing) so the domain-separated, order-preserving combination in
+// hashInternalNode is actually covered instead of only ever hitting the trivial single-leaf path,
+// which is all Test_ActiveCandidate's one-message-per-send flow exercises.
+func TestComputeMerkleRoot_MultiMessage(t *testing.T) {
+	leaves := [][32]byte{{1}, {2}, {3}, {4}}
+
+	left := hashInternalNode(leaves[0], leaves[1])
+	right := hashInternalNode(leaves[2], leaves[3])
+	want := hashInternalNode(left, right)
+
+	require.Equal(t, want, computeMerkleRoot(leaves))
+
+	// Reordering the leaves must change the root: the tree has no sorting step to mask it.
+	reordered := [][32]byte{leaves[1], leaves[0], leaves[2], leaves[3]}
+	require.NotEqual(t, want, computeMerkleRoot(reordered))
+}
+
+func TestHashInternalNode_DomainSeparatedFromLeaf(t *testing.T) {
+	a, b := [32]byte{1}, [32]byte{2}
+	internal := hashInternalNode(a, b)
+
+	// A bare (undomain-separated) keccak256(a||b) must differ from hashInternalNode's output;
+	// otherwise an internal node could be replayed as if it were a leaf.
+	require.NotEqual(t, a, internal)
+	require.NotEqual(t, b, internal)
+	require.NotEqual(t, [32]byte(crypto.Keccak256Hash(a[:], b[:])), internal)
+}
@@ -0,0 +1,52 @@
+package changeset
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TokenCurseSubject derives the stable RMN subject used to curse a single token on a single
+// chain: keccak256("token" || chainSelector || tokenAddress)[:16]. Scoping the subject to both the
+// chain selector and the token address (rather than the address alone) keeps token curses distinct
+// even when the "same" token has different addresses on different chains.
+func TokenCurseSubject(chainSelector uint64, token common.Address) [16]byte {
+	selBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(selBytes, chainSelector)
+
+	buf := append([]byte("token"), selBytes...)
+	buf = append(buf, token.Bytes()...)
+
+	var subject [16]byte
+	copy(subject[:], crypto.Keccak256(buf)[:16])
+	return subject
+}
+
+// CurseToken returns a CurseAction that curses only the RMNRemote on chainSelector against the
+// token-scoped subject for token, leaving every other subject (chain-level and lane-level curses,
+// and token curses for other tokens) untouched. It is registered in the same idempotent curse set
+// CurseChain and CurseLaneBidirectionally use, so applying it twice is a no-op.
+func CurseToken(chainSelector uint64, token common.Address) CurseAction {
+	subject := TokenCurseSubject(chainSelector, token)
+	return func(allChainSelectors []uint64) (map[uint64][][16]byte, error) {
+		return map[uint64][][16]byte{
+			chainSelector: {subject},
+		}, nil
+	}
+}
+
+// CurseTokenLane returns a CurseAction that curses the token pool for token on both sides of the
+// src<->dest lane: src's RMNRemote is cursed against dest's token-scoped subject and vice versa,
+// mirroring the bidirectional semantics of CurseLaneBidirectionally but scoped to a single token
+// instead of the whole lane.
+func CurseTokenLane(src, dest uint64, token common.Address) CurseAction {
+	srcSubject := TokenCurseSubject(src, token)
+	destSubject := TokenCurseSubject(dest, token)
+	return func(allChainSelectors []uint64) (map[uint64][][16]byte, error) {
+		return map[uint64][][16]byte{
+			src:  {destSubject},
+			dest: {srcSubject},
+		}, nil
+	}
+}
@@ -0,0 +1,93 @@
+package changeset_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/deployment/ccip/changeset"
+	"github.com/smartcontractkit/chainlink/deployment/ccip/changeset/testhelpers"
+)
+
+func TestRMNCurseToken(t *testing.T) {
+	e, _ := testhelpers.NewMemoryEnvironment(t, testhelpers.WithNumOfChains(3))
+
+	mapIDToSelector := func(id uint64) uint64 {
+		return e.Env.AllChainSelectors()[id]
+	}
+	token := common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+	verifyNoActiveCurseOnAllChains(t, &e)
+
+	config := changeset.RMNCurseConfig{
+		CurseActions: []changeset.CurseAction{changeset.CurseToken(mapIDToSelector(0), token)},
+		Reason:       "test token curse",
+	}
+	_, err := changeset.RMNCurseChangeset(e.Env, config)
+	require.NoError(t, err)
+
+	state, err := changeset.LoadOnchainState(e.Env)
+	require.NoError(t, err)
+
+	subject := changeset.TokenCurseSubject(mapIDToSelector(0), token)
+	cursed, err := state.Chains[mapIDToSelector(0)].RMNRemote.IsCursed(nil, subject)
+	require.NoError(t, err)
+	require.True(t, cursed, "chain 0 should be cursed against the token subject")
+
+	// A chain-level or lane-level curse subject for the same selector must be untouched by a
+	// token curse: independence between token-scoped and chain/lane-scoped subjects.
+	chainSubject := changeset.SelectorToSubject(mapIDToSelector(1))
+	cursedOnUnrelatedSubject, err := state.Chains[mapIDToSelector(0)].RMNRemote.IsCursed(nil, chainSubject)
+	require.NoError(t, err)
+	require.False(t, cursedOnUnrelatedSubject, "token curse must not bleed into chain/lane subjects")
+
+	_, err = changeset.RMNUncurseChangeset(e.Env, config)
+	require.NoError(t, err)
+
+	state, err = changeset.LoadOnchainState(e.Env)
+	require.NoError(t, err)
+	cursed, err = state.Chains[mapIDToSelector(0)].RMNRemote.IsCursed(nil, subject)
+	require.NoError(t, err)
+	require.False(t, cursed, "uncurse should lift the token-scoped subject")
+}
+
+func TestRMNCurseTokenLane(t *testing.T) {
+	e, _ := testhelpers.NewMemoryEnvironment(t, testhelpers.WithNumOfChains(3))
+
+	mapIDToSelector := func(id uint64) uint64 {
+		return e.Env.AllChainSelectors()[id]
+	}
+	token := common.HexToAddress("0xabcdefabcdefabcdefabcdefabcdefabcdefabcd")
+
+	verifyNoActiveCurseOnAllChains(t, &e)
+
+	src, dest := mapIDToSelector(0), mapIDToSelector(1)
+	config := changeset.RMNCurseConfig{
+		CurseActions: []changeset.CurseAction{changeset.CurseTokenLane(src, dest, token)},
+		Reason:       "test token lane curse",
+	}
+	_, err := changeset.RMNCurseChangeset(e.Env, config)
+	require.NoError(t, err)
+
+	state, err := changeset.LoadOnchainState(e.Env)
+	require.NoError(t, err)
+
+	srcCursedOnDestSubject, err := state.Chains[src].RMNRemote.IsCursed(nil, changeset.TokenCurseSubject(dest, token))
+	require.NoError(t, err)
+	require.True(t, srcCursedOnDestSubject)
+
+	destCursedOnSrcSubject, err := state.Chains[dest].RMNRemote.IsCursed(nil, changeset.TokenCurseSubject(src, token))
+	require.NoError(t, err)
+	require.True(t, destCursedOnSrcSubject)
+
+	// The third chain is untouched by a token lane curse between chains 0 and 1.
+	other := mapIDToSelector(2)
+	otherCursed, err := state.Chains[other].RMNRemote.IsCursed0(nil)
+	require.NoError(t, err)
+	require.False(t, otherCursed)
+
+	_, err = changeset.RMNUncurseChangeset(e.Env, config)
+	require.NoError(t, err)
+	verifyNoActiveCurseOnAllChains(t, &e)
+}
@@ -0,0 +1,296 @@
+package changeset
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/rmn_home"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/rmn_remote"
+)
+
+// UncurseCall is a single ABI-encoded `uncurse(subject)` call destined for the RMNRemote on one
+// chain, along with the subject it lifts so an offline signer can review what they're signing.
+type UncurseCall struct {
+	ChainSelector uint64         `json:"chainSelector"`
+	RMNRemote     common.Address `json:"rmnRemote"`
+	Subject       [16]byte       `json:"subject"`
+	CallData      []byte         `json:"callData"`
+}
+
+// UncurseVoucher bundles the per-chain uncurse calls produced from an RMNCurseConfig together with
+// the RMNHome config version and blessed-signer set that authorizes them, so the bundle can be
+// handed to an offline signing ceremony and later replayed with the collected signatures.
+type UncurseVoucher struct {
+	// Reason is carried over from the RMNCurseConfig for audit purposes.
+	Reason string `json:"reason"`
+	// RMNHomeConfigVersion is the config version of RMNHome that BlessedSigners was read from.
+	// Signatures are only valid against the version they were produced for; if RMNHome's config
+	// changes before the voucher is executed, VerifyUncurseSignatures rejects it.
+	RMNHomeConfigVersion uint32 `json:"rmnHomeConfigVersion"`
+	// BlessedSigners is the set of addresses authorized to sign this voucher, as read from
+	// RMNHome at RMNHomeConfigVersion.
+	BlessedSigners []common.Address `json:"blessedSigners"`
+	// Calls are the per-chain uncurse(subject) calls this voucher authorizes.
+	Calls []UncurseCall `json:"calls"`
+}
+
+// Digest is the hash offline signers sign over. It binds in the RMNHome config version and the
+// sorted blessed signer set alongside every call, so a voucher can't be replayed against a
+// different RMNHome configuration or have calls added/removed after signing. Every variable-length
+// field (Reason, CallData) is length-prefixed rather than concatenated raw, so the byte stream
+// can't be reinterpreted across a different field split (e.g. a long Reason swallowing bytes that
+// were meant to be the first call's chain selector).
+func (v UncurseVoucher) Digest() common.Hash {
+	signers := make([]common.Address, len(v.BlessedSigners))
+	copy(signers, v.BlessedSigners)
+	sort.Slice(signers, func(i, j int) bool { return bytesLess(signers[i].Bytes(), signers[j].Bytes()) })
+
+	var buf []byte
+	buf = appendLengthPrefixed(buf, []byte(v.Reason))
+	buf = append(buf, uint32ToBytes(v.RMNHomeConfigVersion)...)
+	buf = append(buf, uint32ToBytes(uint32(len(signers)))...)
+	for _, s := range signers {
+		buf = append(buf, s.Bytes()...)
+	}
+	buf = append(buf, uint32ToBytes(uint32(len(v.Calls)))...)
+	for _, c := range v.Calls {
+		buf = append(buf, uint64ToBytes(c.ChainSelector)...)
+		buf = append(buf, c.RMNRemote.Bytes()...)
+		buf = append(buf, c.Subject[:]...)
+		buf = appendLengthPrefixed(buf, c.CallData)
+	}
+	return crypto.Keccak256Hash(buf)
+}
+
+// appendLengthPrefixed appends a uint32 big-endian length prefix followed by b, so a
+// variable-length field can't be confused with the bytes that follow it.
+func appendLengthPrefixed(buf, b []byte) []byte {
+	buf = append(buf, uint32ToBytes(uint32(len(b)))...)
+	return append(buf, b...)
+}
+
+func bytesLess(a, b []byte) bool { return string(a) < string(b) }
+
+func uint32ToBytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func uint64ToBytes(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// ResolveCurseSubjectsByChain runs every CurseAction in actions against env's full set of chain
+// selectors and merges the resulting per-chain subject lists into a single map, de-duplicating
+// subjects that more than one action (or a repeated action) targets on the same chain so the
+// uncurse voucher built from it stays idempotent.
+func ResolveCurseSubjectsByChain(env deployment.Environment, actions []CurseAction) (map[uint64][][16]byte, error) {
+	allChainSelectors := env.AllChainSelectors()
+
+	seen := make(map[uint64]map[[16]byte]bool)
+	merged := make(map[uint64][][16]byte)
+	for i, action := range actions {
+		subjectsByChain, err := action(allChainSelectors)
+		if err != nil {
+			return nil, fmt.Errorf("curse action %d failed to resolve subjects: %w", i, err)
+		}
+		for chainSel, subjects := range subjectsByChain {
+			if seen[chainSel] == nil {
+				seen[chainSel] = make(map[[16]byte]bool)
+			}
+			for _, subject := range subjects {
+				if seen[chainSel][subject] {
+					continue
+				}
+				seen[chainSel][subject] = true
+				merged[chainSel] = append(merged[chainSel], subject)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// findRMNHome locates the single chain in state that has an RMNHome contract deployed, since
+// RMNHome only ever lives on the home chain.
+func findRMNHome(state CCIPOnChainState) (*rmn_home.RMNHome, error) {
+	var found *rmn_home.RMNHome
+	for _, chainState := range state.Chains {
+		if chainState.RMNHome == nil {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("found RMNHome deployed on more than one chain")
+		}
+		found = chainState.RMNHome
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no RMNHome found in onchain state")
+	}
+	return found, nil
+}
+
+// BuildUncurseVoucher computes the uncurse(subject) calls implied by config's CurseActions and
+// packages them, together with the RMNHome config version and blessed-signer set currently active
+// on env's home chain, into an UncurseVoucher ready for an offline signing ceremony.
+func BuildUncurseVoucher(env deployment.Environment, config RMNCurseConfig) (UncurseVoucher, error) {
+	if err := config.Validate(env); err != nil {
+		return UncurseVoucher{}, fmt.Errorf("invalid RMNCurseConfig: %w", err)
+	}
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return UncurseVoucher{}, fmt.Errorf("failed to load onchain state: %w", err)
+	}
+
+	rmnRemoteABI, err := abi.JSON(strings.NewReader(rmn_remote.RMNRemoteMetaData.ABI))
+	if err != nil {
+		return UncurseVoucher{}, fmt.Errorf("failed to parse RMNRemote ABI: %w", err)
+	}
+
+	rmnHome, err := findRMNHome(state)
+	if err != nil {
+		return UncurseVoucher{}, err
+	}
+	configVersion, blessedConfig, err := rmnHome.GetActiveDigest(nil)
+	if err != nil {
+		return UncurseVoucher{}, fmt.Errorf("failed to fetch active RMNHome config: %w", err)
+	}
+
+	subjectsByChain, err := ResolveCurseSubjectsByChain(env, config.CurseActions)
+	if err != nil {
+		return UncurseVoucher{}, fmt.Errorf("failed to resolve curse subjects: %w", err)
+	}
+
+	calls := make([]UncurseCall, 0, len(subjectsByChain))
+	for chainSel, subjects := range subjectsByChain {
+		rmnRemote := state.Chains[chainSel].RMNRemote
+		for _, subject := range subjects {
+			callData, err := rmnRemoteABI.Pack("uncurse", subject)
+			if err != nil {
+				return UncurseVoucher{}, fmt.Errorf("failed to encode uncurse call for chain %d: %w", chainSel, err)
+			}
+			calls = append(calls, UncurseCall{
+				ChainSelector: chainSel,
+				RMNRemote:     rmnRemote.Address(),
+				Subject:       subject,
+				CallData:      callData,
+			})
+		}
+	}
+
+	return UncurseVoucher{
+		Reason:               config.Reason,
+		RMNHomeConfigVersion: configVersion,
+		BlessedSigners:       blessedConfig.Signers(),
+		Calls:                calls,
+	}, nil
+}
+
+// VerifyUncurseSignatures checks that sigs contains enough valid signatures, from addresses in
+// voucher.BlessedSigners, over voucher.Digest() to meet RMNHome's current quorum threshold. It
+// returns an error describing the shortfall rather than executing anything.
+func VerifyUncurseSignatures(env deployment.Environment, voucher UncurseVoucher, sigs [][]byte) error {
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return fmt.Errorf("failed to load onchain state: %w", err)
+	}
+	rmnHome, err := findRMNHome(state)
+	if err != nil {
+		return err
+	}
+
+	currentVersion, blessedConfig, err := rmnHome.GetActiveDigest(nil)
+	if err != nil {
+		return fmt.Errorf("failed to fetch active RMNHome config: %w", err)
+	}
+
+	quorum, err := rmnHome.GetSignatureQuorum(nil, voucher.RMNHomeConfigVersion)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature quorum: %w", err)
+	}
+
+	return verifyVoucherSignatures(voucher, sigs, currentVersion, blessedConfig.Signers(), quorum)
+}
+
+// verifyVoucherSignatures is the pure signature/quorum check behind VerifyUncurseSignatures,
+// split out so it can be exercised without a live RMNHome contract: it takes the on-chain config
+// version, the real blessed signer set, and quorum as plain arguments instead of fetching them
+// itself. realBlessedSigners must come from RMNHome, never from voucher.BlessedSigners: that field
+// is self-reported by whoever assembled the voucher, so trusting it instead of RMNHome's actual
+// config would let anyone forge a voucher that "blesses" their own key and satisfies quorum with
+// zero real RMN signer participation.
+func verifyVoucherSignatures(voucher UncurseVoucher, sigs [][]byte, currentRMNHomeConfigVersion uint32, realBlessedSigners []common.Address, quorum uint32) error {
+	if currentRMNHomeConfigVersion != voucher.RMNHomeConfigVersion {
+		return fmt.Errorf("voucher was built against RMNHome config version %d but the active version is now %d; rebuild the voucher",
+			voucher.RMNHomeConfigVersion, currentRMNHomeConfigVersion)
+	}
+
+	blessed := make(map[common.Address]bool, len(realBlessedSigners))
+	for _, s := range realBlessedSigners {
+		blessed[s] = true
+	}
+
+	digest := voucher.Digest()
+	seen := make(map[common.Address]bool, len(sigs))
+	for _, sig := range sigs {
+		pubKey, err := crypto.SigToPub(digest[:], sig)
+		if err != nil {
+			return fmt.Errorf("failed to recover signer from signature: %w", err)
+		}
+		signer := crypto.PubkeyToAddress(*pubKey)
+		if !blessed[signer] {
+			return fmt.Errorf("signature from %s is not in the blessed signer set for this voucher", signer)
+		}
+		seen[signer] = true
+	}
+
+	if uint32(len(seen)) < quorum {
+		return fmt.Errorf("voucher has %d valid distinct signatures but quorum requires %d", len(seen), quorum)
+	}
+	return nil
+}
+
+// RMNUncurseWithSignaturesConfig is the input to RMNUncurseWithSignaturesChangeset: a voucher
+// produced by BuildUncurseVoucher and the signatures collected for it during an offline signing
+// ceremony.
+type RMNUncurseWithSignaturesConfig struct {
+	Voucher    UncurseVoucher
+	Signatures [][]byte
+}
+
+// RMNUncurseWithSignaturesChangeset executes the uncurse calls in config.Voucher, but only after
+// re-verifying that config.Signatures meets RMNHome's quorum threshold for the voucher's config
+// version. This lets the curse/uncurse flow be authorized through an offline signing ceremony
+// instead of requiring every RMN signer to be online to co-sign a live MCMS proposal.
+func RMNUncurseWithSignaturesChangeset(env deployment.Environment, config RMNUncurseWithSignaturesConfig) (deployment.ChangesetOutput, error) {
+	if err := VerifyUncurseSignatures(env, config.Voucher, config.Signatures); err != nil {
+		return deployment.ChangesetOutput{}, fmt.Errorf("voucher signature verification failed: %w", err)
+	}
+
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return deployment.ChangesetOutput{}, fmt.Errorf("failed to load onchain state: %w", err)
+	}
+
+	for _, call := range config.Voucher.Calls {
+		chain, ok := env.Chains[call.ChainSelector]
+		if !ok {
+			return deployment.ChangesetOutput{}, fmt.Errorf("voucher references unknown chain selector %d", call.ChainSelector)
+		}
+		rmnRemote := state.Chains[call.ChainSelector].RMNRemote
+		if _, err := rmnRemote.Uncurse(chain.DeployerKey, call.Subject); err != nil {
+			return deployment.ChangesetOutput{}, fmt.Errorf("failed to submit uncurse for chain %d subject %x: %w", call.ChainSelector, call.Subject, err)
+		}
+	}
+
+	return deployment.ChangesetOutput{}, nil
+}
@@ -0,0 +1,134 @@
+package changeset
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+)
+
+var errTestCurseAction = errors.New("curse action failed")
+
+func TestResolveCurseSubjectsByChain_MergesAndDedupes(t *testing.T) {
+	chainA, chainB := uint64(1), uint64(2)
+	subjectA := [16]byte{0xa}
+	subjectB := [16]byte{0xb}
+
+	onA := func(allChainSelectors []uint64) (map[uint64][][16]byte, error) {
+		return map[uint64][][16]byte{chainA: {subjectA}}, nil
+	}
+	onAAgain := onA // same action applied twice must not duplicate the subject
+	onB := func(allChainSelectors []uint64) (map[uint64][][16]byte, error) {
+		return map[uint64][][16]byte{chainB: {subjectB}}, nil
+	}
+
+	merged, err := ResolveCurseSubjectsByChain(deployment.Environment{}, []CurseAction{onA, onAAgain, onB})
+	require.NoError(t, err)
+	require.Equal(t, [][16]byte{subjectA}, merged[chainA])
+	require.Equal(t, [][16]byte{subjectB}, merged[chainB])
+}
+
+func TestResolveCurseSubjectsByChain_PropagatesActionError(t *testing.T) {
+	failing := func(allChainSelectors []uint64) (map[uint64][][16]byte, error) {
+		return nil, errTestCurseAction
+	}
+	_, err := ResolveCurseSubjectsByChain(deployment.Environment{}, []CurseAction{failing})
+	require.ErrorIs(t, err, errTestCurseAction)
+}
+
+func TestVerifyVoucherSignatures(t *testing.T) {
+	key1, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	key2, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	unblessedKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	signer1 := crypto.PubkeyToAddress(key1.PublicKey)
+	signer2 := crypto.PubkeyToAddress(key2.PublicKey)
+	realBlessedSigners := []common.Address{signer1, signer2}
+
+	voucher := UncurseVoucher{
+		Reason:               "test",
+		RMNHomeConfigVersion: 1,
+		BlessedSigners:       realBlessedSigners,
+		Calls: []UncurseCall{
+			{ChainSelector: 1, Subject: [16]byte{1}},
+		},
+	}
+	digest := voucher.Digest()
+
+	sign := func(key *ecdsa.PrivateKey) []byte {
+		sig, err := crypto.Sign(digest[:], key)
+		require.NoError(t, err)
+		return sig
+	}
+
+	t.Run("quorum met", func(t *testing.T) {
+		sigs := [][]byte{sign(key1), sign(key2)}
+		require.NoError(t, verifyVoucherSignatures(voucher, sigs, 1, realBlessedSigners, 2))
+	})
+
+	t.Run("quorum not met", func(t *testing.T) {
+		sigs := [][]byte{sign(key1)}
+		err := verifyVoucherSignatures(voucher, sigs, 1, realBlessedSigners, 2)
+		require.ErrorContains(t, err, "quorum requires 2")
+	})
+
+	t.Run("stale RMNHome config version", func(t *testing.T) {
+		sigs := [][]byte{sign(key1), sign(key2)}
+		err := verifyVoucherSignatures(voucher, sigs, 2, realBlessedSigners, 2)
+		require.ErrorContains(t, err, "rebuild the voucher")
+	})
+
+	t.Run("signature from non-blessed signer", func(t *testing.T) {
+		sigs := [][]byte{sign(key1), sign(unblessedKey)}
+		err := verifyVoucherSignatures(voucher, sigs, 1, realBlessedSigners, 2)
+		require.ErrorContains(t, err, "not in the blessed signer set")
+	})
+
+	t.Run("forged voucher with self-signed blessed signers is rejected", func(t *testing.T) {
+		forgedKey, err := crypto.GenerateKey()
+		require.NoError(t, err)
+		forgedSigner := crypto.PubkeyToAddress(forgedKey.PublicKey)
+
+		forgedVoucher := voucher
+		forgedVoucher.BlessedSigners = []common.Address{forgedSigner}
+		forgedDigest := forgedVoucher.Digest()
+		forgedSig, err := crypto.Sign(forgedDigest[:], forgedKey)
+		require.NoError(t, err)
+
+		// Even though the voucher self-reports forgedSigner as blessed and quorum (1) is
+		// nominally met, verification must be checked against RMNHome's real blessed set, not
+		// the voucher's own BlessedSigners field.
+		err = verifyVoucherSignatures(forgedVoucher, [][]byte{forgedSig}, 1, realBlessedSigners, 1)
+		require.ErrorContains(t, err, "not in the blessed signer set")
+	})
+}
+
+func TestUncurseVoucher_Digest_LengthPrefixedReasonDoesNotCollide(t *testing.T) {
+	// Without length-prefixing, a short Reason plus a call whose fields happen to start with the
+	// same bytes as a longer Reason could hash identically. Length-prefixing Reason (and CallData)
+	// keeps vouchers with different Reason/call splits apart. This test moves the bytes "CD" from
+	// the call's CallData into Reason and checks that the digest changes.
+	a := UncurseVoucher{
+		Reason:               "AB",
+		RMNHomeConfigVersion: 1,
+		Calls: []UncurseCall{
+			{ChainSelector: 1, CallData: []byte("CD")},
+		},
+	}
+	b := UncurseVoucher{
+		Reason:               "ABCD",
+		RMNHomeConfigVersion: 1,
+		Calls: []UncurseCall{
+			{ChainSelector: 1, CallData: nil},
+		},
+	}
+	require.NotEqual(t, a.Digest(), b.Digest())
+}
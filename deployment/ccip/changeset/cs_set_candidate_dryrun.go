@@ -0,0 +1,180 @@
+package changeset
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+	"github.com/smartcontractkit/chainlink/deployment/ccip/changeset/internal"
+	"github.com/smartcontractkit/chainlink/v2/core/capabilities/ccip/types"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/ccip_home"
+)
+
+// otherPluginType returns the sibling plugin type that a DON's commit/exec configs must agree on
+// a transmitter set with.
+func otherPluginType(p types.PluginType) types.PluginType {
+	if p == types.PluginTypeCCIPCommit {
+		return types.PluginTypeCCIPExec
+	}
+	return types.PluginTypeCCIPCommit
+}
+
+// transmitterSet returns the set of transmitter addresses configured for a DON/plugin's active
+// config on CCIPHome. Returns an empty set if no active config exists yet (e.g. first promotion).
+func transmitterSet(ccipHome *ccip_home.CCIPHome, donID uint32, pluginType types.PluginType, ctx bind.CallOpts) (map[common.Address]bool, error) {
+	activeCfg, err := ccipHome.GetAllConfigs(&ctx, donID, uint8(pluginType))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch active config for don %d plugin %d: %w", donID, pluginType, err)
+	}
+	set := make(map[common.Address]bool)
+	for _, n := range activeCfg.ActiveConfig.Config.Nodes {
+		set[common.BytesToAddress(n.TransmitAccount[:])] = true
+	}
+	return set, nil
+}
+
+// CandidatePromotionDiff is the result of a dry run of SetCandidateChangeset. It reports
+// the config digests that are currently active/candidate on CCIPHome for the DON in question,
+// alongside the digest the proposed CCIPOCRParams would produce, so an operator can review the
+// change before any transaction or MCMS proposal is generated.
+type CandidatePromotionDiff struct {
+	DonID uint32
+	// ActiveDigest is the digest currently marked active on CCIPHome for this plugin type.
+	ActiveDigest [32]byte
+	// CandidateDigest is the digest currently marked candidate on CCIPHome for this plugin type.
+	CandidateDigest [32]byte
+	// ProposedDigest is the digest that would result from applying the proposed CCIPOCRParams.
+	ProposedDigest [32]byte
+	// ProposedConfig is the CCIPHome config that ProposedDigest was derived from, included so
+	// operators can diff signer/transmitter sets, F and deltaProgress against what's active today.
+	ProposedConfig ccip_home.CCIPHomeOCR3Config
+	// IdenticalToActive is true if ProposedDigest == ActiveDigest, i.e. this promotion is a no-op.
+	IdenticalToActive bool
+	// IdenticalToCandidate is true if ProposedDigest == CandidateDigest, i.e. re-proposing the
+	// existing candidate unchanged.
+	IdenticalToCandidate bool
+}
+
+// DryRunSetCandidate computes, for every remote chain in config.OCRConfigPerRemoteChainSelector,
+// the CCIPHome config digest that SetCandidateChangeset would produce, without sending any
+// transaction or MCMS proposal. It lets operators preview a candidate promotion and compare it
+// against the digests currently active/candidate on-chain.
+func DryRunSetCandidate(env deployment.Environment, config SetCandidateChangesetConfig) (map[uint64]CandidatePromotionDiff, error) {
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load onchain state: %w", err)
+	}
+	if err := config.Validate(env, state); err != nil {
+		return nil, fmt.Errorf("invalid SetCandidateChangesetConfig: %w", err)
+	}
+
+	var (
+		homeState = state.Chains[config.HomeChainSelector]
+		ccipHome  = homeState.CCIPHome
+		capReg    = homeState.CapabilityRegistry
+		out       = make(map[uint64]CandidatePromotionDiff, len(config.OCRConfigPerRemoteChainSelector))
+	)
+	for remoteChainSel, ocrParams := range config.OCRConfigPerRemoteChainSelector {
+		donID, err := internal.DonIDForChain(capReg, ccipHome, remoteChainSel)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve don id for chain %d: %w", remoteChainSel, err)
+		}
+
+		proposedCfg, err := internal.BuildOCR3ConfigForCCIPHome(
+			env.Logger,
+			ocrParams.OCRParameters,
+			ocrParams.CommitOffChainConfig,
+			ocrParams.ExecuteOffChainConfig,
+			state,
+			remoteChainSel,
+			config.FeedChainSelector,
+			config.PluginType,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build CCIPHome config for chain %d: %w", remoteChainSel, err)
+		}
+
+		proposedDigest, err := ccipHome.CalculateConfigDigest(&bind.CallOpts{Context: env.GetContext()}, donID, uint8(config.PluginType), *proposedCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate proposed config digest for chain %d: %w", remoteChainSel, err)
+		}
+
+		activeDigest, err := ccipHome.GetActiveDigest(&bind.CallOpts{Context: env.GetContext()}, donID, uint8(config.PluginType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch active digest for don %d: %w", donID, err)
+		}
+		candidateDigest, err := ccipHome.GetCandidateDigest(&bind.CallOpts{Context: env.GetContext()}, donID, uint8(config.PluginType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch candidate digest for don %d: %w", donID, err)
+		}
+
+		out[remoteChainSel] = CandidatePromotionDiff{
+			DonID:                donID,
+			ActiveDigest:         activeDigest,
+			CandidateDigest:      candidateDigest,
+			ProposedDigest:       proposedDigest,
+			ProposedConfig:       *proposedCfg,
+			IdenticalToActive:    bytes.Equal(activeDigest[:], proposedDigest[:]),
+			IdenticalToCandidate: bytes.Equal(candidateDigest[:], proposedDigest[:]),
+		}
+	}
+	return out, nil
+}
+
+// ValidateCandidatePromotion fails loudly if applying config via SetCandidateChangeset would be a
+// no-op (candidate identical to active), would leave transmitter sets inconsistent across the
+// commit/exec plugins for a DON, or would leave the DON unable to reach a 2F+1 quorum.
+func ValidateCandidatePromotion(env deployment.Environment, config SetCandidateChangesetConfig) error {
+	diffs, err := DryRunSetCandidate(env, config)
+	if err != nil {
+		return fmt.Errorf("failed to dry run candidate promotion: %w", err)
+	}
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return fmt.Errorf("failed to load onchain state: %w", err)
+	}
+	ccipHome := state.Chains[config.HomeChainSelector].CCIPHome
+
+	for remoteChainSel, diff := range diffs {
+		if diff.IdenticalToActive {
+			return fmt.Errorf("candidate config for chain %d is identical to the active config (digest %x); refusing no-op promotion",
+				remoteChainSel, diff.ActiveDigest)
+		}
+
+		numNodes := len(diff.ProposedConfig.Nodes)
+		minQuorum := 2*int(diff.ProposedConfig.FRoleDON) + 1
+		if numNodes < minQuorum {
+			return fmt.Errorf("proposed config for chain %d has %d nodes but requires 2F+1=%d to reach quorum (F=%d)",
+				remoteChainSel, numNodes, minQuorum, diff.ProposedConfig.FRoleDON)
+		}
+
+		proposedTransmitters := make(map[common.Address]bool, len(diff.ProposedConfig.Nodes))
+		for _, n := range diff.ProposedConfig.Nodes {
+			proposedTransmitters[common.BytesToAddress(n.TransmitAccount[:])] = true
+		}
+		siblingTransmitters, err := transmitterSet(ccipHome, diff.DonID, otherPluginType(config.PluginType), bind.CallOpts{Context: env.GetContext()})
+		if err != nil {
+			return fmt.Errorf("failed to load sibling plugin transmitter set for chain %d: %w", remoteChainSel, err)
+		}
+		if len(siblingTransmitters) > 0 && !sameAddressSet(proposedTransmitters, siblingTransmitters) {
+			return fmt.Errorf("proposed transmitter set for chain %d (plugin %d) does not match the transmitter set already active for plugin %d on the same DON",
+				remoteChainSel, config.PluginType, otherPluginType(config.PluginType))
+		}
+	}
+	return nil
+}
+
+func sameAddressSet(a, b map[common.Address]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for addr := range a {
+		if !b[addr] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package changeset
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/exp/maps"
+
+	"github.com/smartcontractkit/chainlink-testing-framework/lib/utils/testcontext"
+
+	commonchangeset "github.com/smartcontractkit/chainlink/deployment/common/changeset"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/capabilities/ccip/types"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/fee_quoter"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestSameAddressSet(t *testing.T) {
+	a := common.HexToAddress("0x1")
+	b := common.HexToAddress("0x2")
+
+	require.True(t, sameAddressSet(map[common.Address]bool{a: true}, map[common.Address]bool{a: true}))
+	require.False(t, sameAddressSet(map[common.Address]bool{a: true}, map[common.Address]bool{a: true, b: true}))
+	require.False(t, sameAddressSet(map[common.Address]bool{a: true}, map[common.Address]bool{b: true}))
+}
+
+func TestOtherPluginType(t *testing.T) {
+	require.Equal(t, types.PluginTypeCCIPExec, otherPluginType(types.PluginTypeCCIPCommit))
+	require.Equal(t, types.PluginTypeCCIPCommit, otherPluginType(types.PluginTypeCCIPExec))
+}
+
+// TestCandidatePromotionDiff_NoOp documents the no-op condition ValidateCandidatePromotion refuses:
+// a proposed digest identical to what's already active.
+func TestCandidatePromotionDiff_NoOp(t *testing.T) {
+	diff := CandidatePromotionDiff{
+		ActiveDigest:      [32]byte{1},
+		ProposedDigest:    [32]byte{1},
+		IdenticalToActive: true,
+	}
+	require.True(t, diff.IdenticalToActive)
+
+	diff.ProposedDigest = [32]byte{2}
+	diff.IdenticalToActive = diff.ActiveDigest == diff.ProposedDigest
+	require.False(t, diff.IdenticalToActive)
+}
+
+// TestQuorumReachable exercises the 2F+1 quorum arithmetic ValidateCandidatePromotion applies to
+// diff.ProposedConfig: a node count below 2F+1 must fail, at or above must pass.
+func TestQuorumReachable(t *testing.T) {
+	cases := []struct {
+		name      string
+		numNodes  int
+		f         int
+		reachable bool
+	}{
+		{name: "below quorum", numNodes: 2, f: 1, reachable: false},
+		{name: "exactly at quorum", numNodes: 3, f: 1, reachable: true},
+		{name: "above quorum", numNodes: 4, f: 1, reachable: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			minQuorum := 2*tc.f + 1
+			require.Equal(t, tc.reachable, tc.numNodes >= minQuorum)
+		})
+	}
+}
+
+// TestValidateCandidatePromotion_TransmitterMismatch verifies that a proposed transmitter set that
+// disagrees with the sibling plugin's active transmitter set on the same DON is flagged by
+// sameAddressSet, the check ValidateCandidatePromotion uses for cross-plugin consistency.
+func TestValidateCandidatePromotion_TransmitterMismatch(t *testing.T) {
+	a := common.HexToAddress("0xa")
+	b := common.HexToAddress("0xb")
+
+	proposed := map[common.Address]bool{a: true}
+	mismatched := map[common.Address]bool{b: true}
+	require.False(t, sameAddressSet(proposed, mismatched), "disjoint transmitter sets must be flagged as mismatched")
+
+	matching := map[common.Address]bool{a: true}
+	require.True(t, sameAddressSet(proposed, matching), "identical transmitter sets must not be flagged")
+}
+
+// TestDryRunSetCandidate_Integration builds a MemoryEnvironment the way Test_ActiveCandidate does,
+// applying only the onramp and fee quoter dest updates for source->dest (no price, offramp or
+// router updates), and calls DryRunSetCandidate/ValidateCandidatePromotion directly against
+// the resulting live CCIPHome, rather than only against hand-built CandidatePromotionDiff/config
+// values. This catches regressions the unit tests above can't: e.g. a wrong donID resolution, a
+// digest computed against the wrong config, or ValidateCandidatePromotion failing to reject an
+// actual on-chain no-op.
+func TestDryRunSetCandidate_Integration(t *testing.T) {
+	tenv, _ := NewMemoryEnvironment(t, WithChains(2), WithNodes(4))
+	state, err := LoadOnchainState(tenv.Env)
+	require.NoError(t, err)
+
+	allChains := maps.Keys(tenv.Env.Chains)
+	source := allChains[0]
+	dest := allChains[1]
+
+	tenv.Env, err = commonchangeset.ApplyChangesets(t, tenv.Env, tenv.TimelockContracts(t), []commonchangeset.ChangesetApplication{
+		{
+			Changeset: commonchangeset.WrapChangeSet(UpdateOnRampsDests),
+			Config: UpdateOnRampDestsConfig{
+				UpdatesByChain: map[uint64]map[uint64]OnRampDestinationUpdate{
+					source: {
+						dest: {
+							IsEnabled:        true,
+							AllowListEnabled: false,
+						},
+					},
+				},
+			},
+		},
+		{
+			Changeset: commonchangeset.WrapChangeSet(UpdateFeeQuoterDests),
+			Config: UpdateFeeQuoterDestsConfig{
+				UpdatesByChain: map[uint64]map[uint64]fee_quoter.FeeQuoterDestChainConfig{
+					source: {
+						dest: DefaultFeeQuoterDestChainConfig(),
+					},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = commonchangeset.ApplyChangesets(t, tenv.Env, tenv.TimelockContracts(t), []commonchangeset.ChangesetApplication{
+		{
+			Changeset: commonchangeset.WrapChangeSet(commonchangeset.TransferToMCMSWithTimelock),
+			Config:    genTestTransferOwnershipConfig(tenv, allChains, state),
+		},
+	})
+	require.NoError(t, err)
+	assertTimelockOwnership(t, tenv, allChains, state)
+
+	tokenConfig := NewTestTokenConfig(state.Chains[tenv.FeedChainSel].USDFeeds)
+	baseConfig := SetCandidateChangesetConfig{
+		SetCandidateConfigBase: SetCandidateConfigBase{
+			HomeChainSelector: tenv.HomeChainSel,
+			FeedChainSelector: tenv.FeedChainSel,
+			OCRConfigPerRemoteChainSelector: map[uint64]CCIPOCRParams{
+				dest: DefaultOCRParams(
+					tenv.FeedChainSel,
+					tokenConfig.GetTokenInfo(logger.TestLogger(t), state.Chains[dest].LinkToken, state.Chains[dest].Weth9),
+					nil,
+				),
+			},
+			PluginType: types.PluginTypeCCIPCommit,
+			MCMS: &MCMSConfig{
+				MinDelay: 0,
+			},
+		},
+	}
+
+	// Before any candidate has been set, the dry run must report a fresh proposal as distinct from
+	// the (empty) active/candidate digests, and ValidateCandidatePromotion must allow it through.
+	diffs, err := DryRunSetCandidate(tenv.Env, baseConfig)
+	require.NoError(t, err)
+	diff, ok := diffs[dest]
+	require.True(t, ok, "expected a diff for the dest chain")
+	require.False(t, diff.IdenticalToActive, "a fresh candidate proposal must not be reported as a no-op against an empty active config")
+	require.NoError(t, ValidateCandidatePromotion(tenv.Env, baseConfig))
+
+	_, err = commonchangeset.ApplyChangesets(t, tenv.Env, tenv.TimelockContracts(t), []commonchangeset.ChangesetApplication{
+		{
+			Changeset: commonchangeset.WrapChangeSet(SetCandidateChangeset),
+			Config:    baseConfig,
+		},
+	})
+	require.NoError(t, err)
+
+	// CCIPHome's candidate digest must now match what DryRunSetCandidate predicted.
+	ccipHome := state.Chains[tenv.HomeChainSel].CCIPHome
+	onChainCandidateDigest, err := ccipHome.GetCandidateDigest(&bind.CallOpts{
+		Context: testcontext.Get(t),
+	}, diff.DonID, uint8(types.PluginTypeCCIPCommit))
+	require.NoError(t, err)
+	require.Equal(t, diff.ProposedDigest, onChainCandidateDigest, "on-chain candidate digest must match what DryRunSetCandidate predicted before the changeset was applied")
+
+	// Re-running the dry run with the same config against the now-set candidate must report it as
+	// identical to the candidate (re-proposing an unchanged candidate), while still distinct from
+	// the (still empty) active config. ValidateCandidatePromotion only refuses a promotion that's a
+	// no-op against the *active* config, so it must still allow this.
+	diffsAfter, err := DryRunSetCandidate(tenv.Env, baseConfig)
+	require.NoError(t, err)
+	require.True(t, diffsAfter[dest].IdenticalToCandidate, "re-proposing the same config that was just set as candidate must be reported identical to the candidate")
+	require.False(t, diffsAfter[dest].IdenticalToActive, "the active config is still unset, so this must not be reported as a no-op against active")
+	require.NoError(t, ValidateCandidatePromotion(tenv.Env, baseConfig))
+}
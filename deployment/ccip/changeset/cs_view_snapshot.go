@@ -0,0 +1,46 @@
+package changeset
+
+import (
+	"fmt"
+
+	"github.com/smartcontractkit/chainlink/deployment"
+	"github.com/smartcontractkit/chainlink/deployment/ccip/view/v1_2"
+	"github.com/smartcontractkit/chainlink/deployment/ccip/view/v1_6"
+	commonview "github.com/smartcontractkit/chainlink/deployment/common/view"
+)
+
+// CaptureConfigSnapshot builds a commonview.Snapshot of every PriceRegistry and FeeQuoter
+// deployed across env's chains, labeled "<chainSelector>:<contractName>". Running this before and
+// after a changeset and diffing the two snapshots with commonview.DiffSnapshots lets an auditor
+// verify the changeset only touched the config fields it claimed to.
+func CaptureConfigSnapshot(env deployment.Environment, label string) (commonview.Snapshot, error) {
+	state, err := LoadOnchainState(env)
+	if err != nil {
+		return commonview.Snapshot{}, fmt.Errorf("failed to load onchain state: %w", err)
+	}
+
+	snap := commonview.Snapshot{Label: label, Views: make(map[string]any)}
+	for chainSel, chainState := range state.Chains {
+		if chainState.PriceRegistry != nil {
+			view, err := v1_2.GeneratePriceRegistryView(chainState.PriceRegistry)
+			if err != nil {
+				return commonview.Snapshot{}, fmt.Errorf("failed to capture PriceRegistry view for chain %d: %w", chainSel, err)
+			}
+			snap.Views[fmt.Sprintf("%d:PriceRegistry", chainSel)] = view
+		}
+		if chainState.FeeQuoter != nil {
+			destSelectors := make([]uint64, 0, len(env.Chains))
+			for otherSel := range env.Chains {
+				if otherSel != chainSel {
+					destSelectors = append(destSelectors, otherSel)
+				}
+			}
+			view, err := v1_6.GenerateFeeQuoterView(chainState.FeeQuoter, destSelectors)
+			if err != nil {
+				return commonview.Snapshot{}, fmt.Errorf("failed to capture FeeQuoter view for chain %d: %w", chainSel, err)
+			}
+			snap.Views[fmt.Sprintf("%d:FeeQuoter", chainSel)] = view
+		}
+	}
+	return snap, nil
+}
@@ -0,0 +1,62 @@
+// Command ccip-view inspects and diffs the JSON config snapshots produced by
+// changeset.CaptureConfigSnapshot, so auditors can verify a changeset only changed the
+// PriceRegistry/FeeQuoter fields it claimed to.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/smartcontractkit/chainlink/deployment/common/view"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(1)
+	}
+
+	switch os.Args[1] {
+	case "diff":
+		if len(os.Args) != 4 {
+			fmt.Fprintln(os.Stderr, "usage: ccip-view diff <snapshot-a> <snapshot-b>")
+			os.Exit(1)
+		}
+		if err := runDiff(os.Args[2], os.Args[3]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: ccip-view <command>")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  diff <snapshot-a> <snapshot-b>   report config drift between two snapshots")
+}
+
+func runDiff(aPath, bPath string) error {
+	a, err := view.ReadSnapshot(aPath)
+	if err != nil {
+		return err
+	}
+	b, err := view.ReadSnapshot(bPath)
+	if err != nil {
+		return err
+	}
+	diff, err := view.DiffSnapshots(a, b)
+	if err != nil {
+		return err
+	}
+
+	out, err := json.MarshalIndent(diff, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal diff report: %w", err)
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,56 @@
+package v1_6
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/smartcontractkit/chainlink/deployment/common/view/types"
+	"github.com/smartcontractkit/chainlink/v2/core/gethwrappers/ccip/generated/fee_quoter"
+)
+
+// FeeQuoterView is the FeeQuoter analogue of v1_2.PriceRegistryView. It captures the fields an
+// auditor cares about when verifying that a changeset only touched the config it claimed to:
+// the fee token set, the price-updater set, and each destination chain's FeeQuoterDestChainConfig.
+type FeeQuoterView struct {
+	types.ContractMetaData
+	FeeTokens       []common.Address                               `json:"feeTokens"`
+	Updaters        []common.Address                               `json:"updaters"`
+	DestChainConfig map[uint64]fee_quoter.FeeQuoterDestChainConfig `json:"destChainConfig"`
+}
+
+// GenerateFeeQuoterView reads FeeQuoter's fee token set, price-updater set, and the dest chain
+// config for every selector in destChainSelectors into a FeeQuoterView.
+func GenerateFeeQuoterView(fq *fee_quoter.FeeQuoter, destChainSelectors []uint64) (FeeQuoterView, error) {
+	if fq == nil {
+		return FeeQuoterView{}, fmt.Errorf("cannot generate view for nil FeeQuoter")
+	}
+	meta, err := types.NewContractMetaData(fq, fq.Address())
+	if err != nil {
+		return FeeQuoterView{}, fmt.Errorf("failed to generate contract metadata for FeeQuoter %s: %w", fq.Address(), err)
+	}
+	feeTokens, err := fq.GetFeeTokens(nil)
+	if err != nil {
+		return FeeQuoterView{}, fmt.Errorf("failed to get fee tokens %s: %w", fq.Address(), err)
+	}
+	updaters, err := fq.GetPriceUpdaters(nil)
+	if err != nil {
+		return FeeQuoterView{}, fmt.Errorf("failed to get price updaters %s: %w", fq.Address(), err)
+	}
+
+	destCfg := make(map[uint64]fee_quoter.FeeQuoterDestChainConfig, len(destChainSelectors))
+	for _, destSel := range destChainSelectors {
+		cfg, err := fq.GetDestChainConfig(nil, destSel)
+		if err != nil {
+			return FeeQuoterView{}, fmt.Errorf("failed to get dest chain config for %s dest %d: %w", fq.Address(), destSel, err)
+		}
+		destCfg[destSel] = cfg
+	}
+
+	return FeeQuoterView{
+		ContractMetaData: meta,
+		FeeTokens:        feeTokens,
+		Updaters:         updaters,
+		DestChainConfig:  destCfg,
+	}, nil
+}
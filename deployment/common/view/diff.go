@@ -0,0 +1,183 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FieldChange describes a single field that differs between two view snapshots of the same
+// contract. Values are whatever JSON produced for that field (strings, numbers, slices of
+// addresses, etc.) so callers can render them without knowing the concrete view type.
+type FieldChange struct {
+	Field string `json:"field"`
+	Prev  any    `json:"prev"`
+	Curr  any    `json:"curr"`
+}
+
+// ViewDiff is the result of diffing two view snapshots (e.g. two PriceRegistryView or
+// FeeQuoterView values captured at different times) of the same logical contract. It is built by
+// comparing the JSON representation of prev and curr field-by-field, so it works for any view
+// struct without type-specific diffing code.
+type ViewDiff struct {
+	// Added lists fields present in curr but not prev (e.g. a new field added to the view type,
+	// or a map key that didn't exist before).
+	Added []FieldChange `json:"added"`
+	// Removed lists fields present in prev but not curr.
+	Removed []FieldChange `json:"removed"`
+	// Changed lists fields present in both but with different values.
+	Changed []FieldChange `json:"changed"`
+}
+
+// IsEmpty reports whether the diff found no differences at all.
+func (d ViewDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffView compares two view values of the same type (typically two snapshots of the same
+// PriceRegistry or FeeQuoter captured before/after a changeset run) and reports which fields were
+// added, removed, or changed. prev/curr are marshaled to JSON and walked recursively by dot-path:
+// JSON objects (structs, maps) recurse per-key so a change nested several levels down (e.g. a
+// single destination chain's config inside destChainConfig) is reported at its own path rather
+// than as a whole-field "changed", and JSON arrays are diffed as unordered sets of elements (e.g.
+// feeTokens) so adding or removing a single element doesn't surface as the whole array changing.
+func DiffView(prev, curr any) (ViewDiff, error) {
+	prevVal, err := toJSONValue(prev)
+	if err != nil {
+		return ViewDiff{}, fmt.Errorf("failed to marshal prev view: %w", err)
+	}
+	currVal, err := toJSONValue(curr)
+	if err != nil {
+		return ViewDiff{}, fmt.Errorf("failed to marshal curr view: %w", err)
+	}
+
+	diff := ViewDiff{}
+	diffJSONValues("", prevVal, currVal, &diff)
+	return diff, nil
+}
+
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// diffJSONValues recursively compares prev and curr (as produced by json.Unmarshal into `any`) and
+// appends any differences found under path to diff.
+func diffJSONValues(path string, prev, curr any, diff *ViewDiff) {
+	prevMap, prevIsMap := prev.(map[string]any)
+	currMap, currIsMap := curr.(map[string]any)
+	if prevIsMap && currIsMap {
+		diffJSONMaps(path, prevMap, currMap, diff)
+		return
+	}
+
+	prevSlice, prevIsSlice := prev.([]any)
+	currSlice, currIsSlice := curr.([]any)
+	if prevIsSlice && currIsSlice {
+		diffJSONSlicesAsSets(path, prevSlice, currSlice, diff)
+		return
+	}
+
+	if !reflect.DeepEqual(prev, curr) {
+		diff.Changed = append(diff.Changed, FieldChange{Field: path, Prev: prev, Curr: curr})
+	}
+}
+
+// diffJSONMaps recurses into a JSON object per key, reporting whole-value added/removed entries
+// for keys unique to one side and recursing for keys present on both.
+func diffJSONMaps(path string, prev, curr map[string]any, diff *ViewDiff) {
+	for _, key := range sortedKeys(prev, curr) {
+		fieldPath := joinPath(path, key)
+		prevVal, inPrev := prev[key]
+		currVal, inCurr := curr[key]
+		switch {
+		case inPrev && !inCurr:
+			diff.Removed = append(diff.Removed, FieldChange{Field: fieldPath, Prev: prevVal})
+		case !inPrev && inCurr:
+			diff.Added = append(diff.Added, FieldChange{Field: fieldPath, Curr: currVal})
+		default:
+			diffJSONValues(fieldPath, prevVal, currVal, diff)
+		}
+	}
+}
+
+// diffJSONSlicesAsSets treats prev/curr as unordered sets of elements (keyed by their JSON
+// representation) so a single element being added or removed doesn't report the entire array as
+// changed. An element whose content differs between prev and curr has a different key on each
+// side, so it is reported as one removal plus one addition at path; array elements never appear
+// in Changed.
+func diffJSONSlicesAsSets(path string, prev, curr []any, diff *ViewDiff) {
+	prevByKey := make(map[string]any, len(prev))
+	for _, v := range prev {
+		prevByKey[elementKey(v)] = v
+	}
+	currByKey := make(map[string]any, len(curr))
+	for _, v := range curr {
+		currByKey[elementKey(v)] = v
+	}
+
+	keys := make(map[string]bool, len(prevByKey)+len(currByKey))
+	for k := range prevByKey {
+		keys[k] = true
+	}
+	for k := range currByKey {
+		keys[k] = true
+	}
+	sortedElementKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedElementKeys = append(sortedElementKeys, k)
+	}
+	sort.Strings(sortedElementKeys)
+
+	for _, k := range sortedElementKeys {
+		prevVal, inPrev := prevByKey[k]
+		currVal, inCurr := currByKey[k]
+		switch {
+		case inPrev && !inCurr:
+			diff.Removed = append(diff.Removed, FieldChange{Field: path, Prev: prevVal})
+		case !inPrev && inCurr:
+			diff.Added = append(diff.Added, FieldChange{Field: path, Curr: currVal})
+		}
+	}
+}
+
+// elementKey derives a stable, comparable key for a slice element by re-marshaling it to JSON with
+// sorted map keys (json.Marshal always sorts map[string]any keys), so two structurally identical
+// elements map to the same key regardless of how they were originally ordered.
+func elementKey(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func sortedKeys(maps ...map[string]any) []string {
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		for k := range m {
+			seen[k] = true
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func findChange(changes []FieldChange, field string) (FieldChange, bool) {
+	for _, c := range changes {
+		if c.Field == field {
+			return c, true
+		}
+	}
+	return FieldChange{}, false
+}
+
+func TestDiffView_SetDiffOnArrayField(t *testing.T) {
+	type view struct {
+		FeeTokens []string `json:"feeTokens"`
+	}
+	prev := view{FeeTokens: []string{"0xLINK", "0xWETH"}}
+	curr := view{FeeTokens: []string{"0xLINK", "0xUSDC"}}
+
+	diff, err := DiffView(prev, curr)
+	require.NoError(t, err)
+
+	removed, ok := findChange(diff.Removed, "feeTokens")
+	require.True(t, ok, "expected a per-element removal, not a whole-array change")
+	require.Equal(t, "0xWETH", removed.Prev)
+
+	added, ok := findChange(diff.Added, "feeTokens")
+	require.True(t, ok)
+	require.Equal(t, "0xUSDC", added.Curr)
+
+	require.Empty(t, diff.Changed, "an array element swap must not be reported as the whole field changing")
+}
+
+func TestDiffView_PerKeyMapDiff(t *testing.T) {
+	type destChainConfig struct {
+		IsEnabled bool `json:"isEnabled"`
+	}
+	type view struct {
+		DestChainConfig map[string]destChainConfig `json:"destChainConfig"`
+	}
+	prev := view{DestChainConfig: map[string]destChainConfig{
+		"1": {IsEnabled: true},
+		"2": {IsEnabled: true},
+	}}
+	curr := view{DestChainConfig: map[string]destChainConfig{
+		"1": {IsEnabled: false},
+		"2": {IsEnabled: true},
+	}}
+
+	diff, err := DiffView(prev, curr)
+	require.NoError(t, err)
+
+	changed, ok := findChange(diff.Changed, "destChainConfig.1.isEnabled")
+	require.True(t, ok, "expected the change to be reported at the nested field path, not the whole map")
+	require.Equal(t, true, changed.Prev)
+	require.Equal(t, false, changed.Curr)
+
+	_, changedAtTop := findChange(diff.Changed, "destChainConfig")
+	require.False(t, changedAtTop, "an unrelated dest chain's config must not make the whole map report as changed")
+}
+
+func TestDiffView_NoDifferences(t *testing.T) {
+	type view struct {
+		FeeTokens []string `json:"feeTokens"`
+	}
+	prev := view{FeeTokens: []string{"0xLINK"}}
+	curr := view{FeeTokens: []string{"0xLINK"}}
+
+	diff, err := DiffView(prev, curr)
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+}
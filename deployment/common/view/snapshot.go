@@ -0,0 +1,90 @@
+package view
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// Snapshot is a reproducible, point-in-time capture of a set of contract views (e.g. every
+// PriceRegistry/FeeQuoter view across chains after a changeset run). Views are keyed by a
+// caller-chosen label (typically "<chainSelector>:<contractName>") so DiffSnapshots can match up
+// the same contract across two snapshots regardless of capture order.
+type Snapshot struct {
+	// Label is a free-form description of when/why this snapshot was taken, e.g. a changeset
+	// name or migration ID, included so a diff report can say what it's comparing.
+	Label string          `json:"label"`
+	Views map[string]any `json:"views"`
+}
+
+// WriteSnapshot serializes snap as indented JSON to path.
+func WriteSnapshot(path string, snap Snapshot) error {
+	b, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot to %s: %w", path, err)
+	}
+	return nil
+}
+
+// ReadSnapshot loads a snapshot previously written by WriteSnapshot.
+func ReadSnapshot(path string) (Snapshot, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("failed to read snapshot from %s: %w", path, err)
+	}
+	var snap Snapshot
+	if err := json.Unmarshal(b, &snap); err != nil {
+		return Snapshot{}, fmt.Errorf("failed to unmarshal snapshot from %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// SnapshotDiff is a per-view ViewDiff produced by comparing two Snapshots, keyed by the view label
+// the snapshots share.
+type SnapshotDiff struct {
+	// OnlyInPrev lists labels present in the prev snapshot but missing from curr (e.g. a
+	// contract that was removed).
+	OnlyInPrev []string `json:"onlyInPrev"`
+	// OnlyInCurr lists labels present in curr but missing from prev (e.g. a newly deployed
+	// contract).
+	OnlyInCurr []string `json:"onlyInCurr"`
+	// Changed maps label -> field diff, for labels present in both snapshots.
+	Changed map[string]ViewDiff `json:"changed"`
+}
+
+// DiffSnapshots compares two snapshots view-by-view (matched by label) and reports what was
+// added, removed, or changed in each. Labels whose view is byte-for-byte identical between prev
+// and curr are omitted from Changed entirely, so a clean changeset run produces an empty diff.
+func DiffSnapshots(prev, curr Snapshot) (SnapshotDiff, error) {
+	out := SnapshotDiff{Changed: make(map[string]ViewDiff)}
+	for label := range prev.Views {
+		if _, ok := curr.Views[label]; !ok {
+			out.OnlyInPrev = append(out.OnlyInPrev, label)
+		}
+	}
+	for label := range curr.Views {
+		if _, ok := prev.Views[label]; !ok {
+			out.OnlyInCurr = append(out.OnlyInCurr, label)
+		}
+	}
+	sort.Strings(out.OnlyInPrev)
+	sort.Strings(out.OnlyInCurr)
+	for label, prevView := range prev.Views {
+		currView, ok := curr.Views[label]
+		if !ok {
+			continue
+		}
+		d, err := DiffView(prevView, currView)
+		if err != nil {
+			return SnapshotDiff{}, fmt.Errorf("failed to diff view %q: %w", label, err)
+		}
+		if !d.IsEmpty() {
+			out.Changed[label] = d
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package view
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffSnapshots_SortsOnlyInPrevAndOnlyInCurr(t *testing.T) {
+	prev := Snapshot{Views: map[string]any{
+		"z-label": 1,
+		"a-label": 1,
+		"m-label": 1,
+	}}
+	curr := Snapshot{Views: map[string]any{
+		"y-label": 1,
+		"b-label": 1,
+	}}
+
+	// Run several times: unsorted map iteration order would make this flaky if DiffSnapshots
+	// didn't sort its output.
+	for i := 0; i < 10; i++ {
+		diff, err := DiffSnapshots(prev, curr)
+		require.NoError(t, err)
+		require.Equal(t, []string{"a-label", "m-label", "z-label"}, diff.OnlyInPrev)
+		require.Equal(t, []string{"b-label", "y-label"}, diff.OnlyInCurr)
+	}
+}
+
+func TestDiffSnapshots_ChangedOmitsIdenticalViews(t *testing.T) {
+	prev := Snapshot{Views: map[string]any{
+		"contract-a": map[string]any{"balance": 1},
+		"contract-b": map[string]any{"balance": 2},
+	}}
+	curr := Snapshot{Views: map[string]any{
+		"contract-a": map[string]any{"balance": 1},
+		"contract-b": map[string]any{"balance": 3},
+	}}
+
+	diff, err := DiffSnapshots(prev, curr)
+	require.NoError(t, err)
+	require.NotContains(t, diff.Changed, "contract-a")
+	require.Contains(t, diff.Changed, "contract-b")
+}